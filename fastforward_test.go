@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+
+	"gameOfLife/internal/hashlife"
+)
+
+func newHashlifeTestGame(size int) *gameOfLife {
+	g := newTestGame(size)
+	g.engine = EngineHashlife
+	g.world = hashlife.NewWorld(hashlife.Rule(g.rule))
+	return g
+}
+
+func TestFastForwardAdvancesByMacroJump(t *testing.T) {
+	g := newHashlifeTestGame(32)
+	g.currentState[2][4], g.currentState[3][4], g.currentState[4][4] = true, true, true
+
+	startGen := g.generation
+	jump := g.FastForward()
+	if jump <= 1 {
+		t.Fatalf("expected FastForward to report a macro-jump of more than 1 generation, got %d", jump)
+	}
+	if g.generation != startGen+jump {
+		t.Errorf("g.generation = %d, want %d (startGen + reported jump)", g.generation, startGen+jump)
+	}
+}
+
+func TestFastForwardNoopInWrapMode(t *testing.T) {
+	g := newHashlifeTestGame(32)
+	g.wrap = true
+	startGen := g.generation
+	if jump := g.FastForward(); jump != 0 {
+		t.Errorf("FastForward in wrap mode should be a no-op, got jump %d", jump)
+	}
+	if g.generation != startGen {
+		t.Errorf("g.generation changed in wrap mode: got %d, want %d", g.generation, startGen)
+	}
+}
+
+func TestFastForwardUndoRevertsWholeJump(t *testing.T) {
+	g := newHashlifeTestGame(32)
+	g.currentState[2][4], g.currentState[3][4], g.currentState[4][4] = true, true, true
+	before := make([][]bool, g.size)
+	for x := range before {
+		before[x] = append([]bool(nil), g.currentState[x]...)
+	}
+
+	g.FastForward()
+	if !g.Undo() {
+		t.Fatalf("Undo: expected the fast-forward jump to be a single undoable entry")
+	}
+	for x := range before {
+		for y := range before[x] {
+			if g.currentState[x][y] != before[x][y] {
+				t.Fatalf("Undo after FastForward didn't restore the pre-jump board at (%d,%d)", x, y)
+			}
+		}
+	}
+}
@@ -0,0 +1,511 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"gameOfLife/internal/bitboard"
+	"gameOfLife/internal/hashlife"
+)
+
+// Shared simulation state and engine, used by both the desktop (pixel/glfw)
+// and WebAssembly (syscall/js) frontends, which each bring their own
+// rendering and input handling in main.go / main_js.go.
+
+const (
+	cells    = 100 // initial/default board size
+	minCells = 20
+	// maxCells is a deliberate interim cap, not the request's eventual
+	// "up to 4096x4096": draw() (main.go) still redraws every live cell
+	// every frame, so it — not stepHashlife/stepBitboard — is the
+	// bottleneck at current sizes. Raise this once the renderer is no
+	// longer what a larger board would actually be waiting on.
+	maxCells       = 150
+	cellSizeStep   = 10
+	cellWidth      = 10
+	initalCellsPct = 0.3
+	fps            = 30 // render rate; simulation rate is g.ticksPerSecond
+
+	defaultTicksPerSecond = fps // matches the pre-decoupling behavior
+	minTicksPerSecond     = 1
+	maxTicksPerSecond     = 60
+	ticksPerSecondStep    = 5
+
+	defaultHistoryDepth = 200    // how many generations/edits Undo can step back through
+	maxJumpSteps        = 100000 // safety bound on how far JumpToGeneration will simulate forward
+)
+
+// Engine selects which implementation calculateNextState uses for the
+// bounded (non-wrap) topology. EngineBitboard exists alongside the default
+// EngineHashlife so the two can be benchmarked against each other; see
+// stepHashlife and stepBitboard.
+const (
+	EngineHashlife = "hashlife"
+	EngineBitboard = "bitboard"
+)
+
+var (
+	paused = false
+)
+
+// Rule is a B/S (birth/survival) rulestring such as "B3/S23", represented
+// as neighbor-count masks so calculateNextState never has to parse or
+// branch on notation while stepping the simulation.
+type Rule struct {
+	Birth   [9]bool
+	Survive [9]bool
+}
+
+// rulePresets are the built-in rulestrings cyclable from the menu.
+var rulePresets = []struct {
+	Name string
+	Rule string
+}{
+	{"Conway's Life", "B3/S23"},
+	{"HighLife", "B36/S23"},
+	{"Seeds", "B2/S"},
+	{"Day & Night", "B3678/S34678"},
+}
+
+// ParseRule parses the standard B/S notation, e.g. "B3/S23" or "B2/S".
+func ParseRule(s string) (Rule, error) {
+	var r Rule
+	s = strings.TrimSpace(s)
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 {
+		return r, fmt.Errorf("rule %q: expected B.../S... notation", s)
+	}
+	bPart, sPart := parts[0], parts[1]
+	if !strings.HasPrefix(bPart, "B") || !strings.HasPrefix(sPart, "S") {
+		return r, fmt.Errorf("rule %q: expected B before / and S after", s)
+	}
+	if err := parseDigits(bPart[1:], &r.Birth); err != nil {
+		return r, fmt.Errorf("rule %q: birth: %w", s, err)
+	}
+	if err := parseDigits(sPart[1:], &r.Survive); err != nil {
+		return r, fmt.Errorf("rule %q: survive: %w", s, err)
+	}
+	return r, nil
+}
+
+func parseDigits(digits string, mask *[9]bool) error {
+	for _, c := range digits {
+		if c < '0' || c > '8' {
+			return fmt.Errorf("invalid neighbor count %q", c)
+		}
+		mask[c-'0'] = true
+	}
+	return nil
+}
+
+// String renders r back into B/S notation.
+func (r Rule) String() string {
+	var b, s strings.Builder
+	for n := 0; n <= 8; n++ {
+		if r.Birth[n] {
+			fmt.Fprintf(&b, "%d", n)
+		}
+		if r.Survive[n] {
+			fmt.Fprintf(&s, "%d", n)
+		}
+	}
+	return fmt.Sprintf("B%s/S%s", b.String(), s.String())
+}
+
+type gameOfLife struct {
+	currentState [][]bool
+	nextState    [][]bool
+	size         int // current board is size x size cells; see Resize
+
+	wrap           bool // torus topology instead of a bounded board
+	ticksPerSecond int  // simulation rate, decoupled from the render rate (fps)
+
+	generation   int                  // count of generations elapsed since initialize/reset
+	history      []generationSnapshot // bounded undo stack, oldest first, evicted once over historyDepth
+	redo         []generationSnapshot // states undone by Undo, replayable by Redo until the next edit/step
+	historyDepth int
+
+	historyInputMode bool
+	historyInputText string
+
+	engine string          // EngineHashlife or EngineBitboard; see stepHashlife/stepBitboard
+	world  *hashlife.World // backs stepHashlife; rebuilt whenever rule changes
+
+	rule        Rule
+	pendingRule *Rule // swapped in at the start of the next calculateNextState, so a rule change mid-run never tears a generation
+
+	ruleIndex     int
+	ruleInputMode bool
+	ruleInputText string
+
+	patternInputMode   bool
+	patternInputAction string // "load" or "save"
+	patternInputText   string
+	ruleMismatch       string // non-empty while waiting on a Y/N prompt to switch to this rulestring
+}
+
+// SetAlive implements the board interface patterns.Pattern.StampInto
+// paints through, so the patterns package never needs to know about
+// gameOfLife's internal grid representation.
+func (g *gameOfLife) SetAlive(x, y int, alive bool) {
+	g.currentState[x][y] = alive
+}
+
+// Bounds implements the board interface for patterns.Pattern.StampInto.
+func (g *gameOfLife) Bounds() (w, h int) {
+	return g.size, g.size
+}
+
+func (g *gameOfLife) initialize() {
+	g.size = cells
+	g.ticksPerSecond = defaultTicksPerSecond
+	g.historyDepth = defaultHistoryDepth
+	if g.engine == "" {
+		g.engine = EngineHashlife
+	}
+	g.generation = 0
+	g.history = nil
+	g.redo = nil
+	g.currentState = make([][]bool, g.size)
+	g.nextState = make([][]bool, g.size)
+	for i := range g.currentState {
+		g.currentState[i] = make([]bool, g.size)
+		g.nextState[i] = make([]bool, g.size)
+	}
+
+	g.rule, _ = ParseRule(rulePresets[0].Rule)
+	g.world = hashlife.NewWorld(hashlife.Rule(g.rule))
+
+	for i := 0; i < g.size; i++ {
+		for j := 0; j < g.size; j++ {
+			r := rand.Float32()
+			if r <= initalCellsPct {
+				g.currentState[i][j] = true
+			} else {
+				g.currentState[i][j] = false
+			}
+		}
+	}
+}
+
+// Resize changes the board to size x size cells, reallocating
+// currentState/nextState and copying whatever of the old board overlaps
+// the new one (both anchored at the origin). size is clamped to
+// [minCells, maxCells].
+func (g *gameOfLife) Resize(size int) {
+	if size < minCells {
+		size = minCells
+	} else if size > maxCells {
+		size = maxCells
+	}
+	if size == g.size {
+		return
+	}
+
+	next := make([][]bool, size)
+	for i := range next {
+		next[i] = make([]bool, size)
+	}
+	overlap := size
+	if g.size < overlap {
+		overlap = g.size
+	}
+	for i := 0; i < overlap; i++ {
+		copy(next[i], g.currentState[i])
+	}
+
+	g.size = size
+	g.currentState = next
+	g.nextState = make([][]bool, size)
+	for i := range g.nextState {
+		g.nextState[i] = make([]bool, size)
+	}
+}
+
+// SetRule stages a rule change to take effect at the start of the next
+// calculateNextState call, so the transition function never changes
+// partway through a generation.
+func (g *gameOfLife) SetRule(r Rule) {
+	g.pendingRule = &r
+}
+
+// ToggleCell flips (x, y) and records the board's prior state as an
+// undoable step, so experimenting with patterns by hand while paused is
+// non-destructive.
+func (g *gameOfLife) ToggleCell(x, y int) {
+	g.recordHistory()
+	g.currentState[x][y] = !g.currentState[x][y]
+}
+
+// calculateNextState advances the board exactly one generation — callers
+// (the single-step hotkey, Undo/Redo, JumpToGeneration) all depend on
+// g.generation changing by exactly 1 per call. In the default bounded
+// topology it uses g.engine (hashlife by default, or the bit-packed
+// bitboard baseline for benchmarking against it — see stepHashlife and
+// stepBitboard). In wrap mode there is no bounded-board equivalent of
+// either engine's edge handling, so it falls back to direct neighbor
+// counting on a torus instead.
+func (g *gameOfLife) calculateNextState() {
+	// recordHistory must snapshot the pre-step board (and the rule that
+	// produced it) before any pendingRule swap below, or Undo would tag
+	// this generation with the rule it's about to change to instead of
+	// the rule it was actually simulated under.
+	g.recordHistory()
+
+	if g.pendingRule != nil {
+		g.rule = *g.pendingRule
+		g.pendingRule = nil
+		g.world = hashlife.NewWorld(hashlife.Rule(g.rule))
+	}
+
+	switch {
+	case g.wrap:
+		g.stepToroidal()
+	case g.engine == EngineBitboard:
+		g.stepBitboard()
+	default:
+		g.stepHashlife()
+	}
+	g.currentState, g.nextState = g.nextState, g.currentState
+	g.generation++
+}
+
+// stepHashlife fills nextState with the board advanced exactly one
+// generation via the hashlife quadtree engine instead of scanning every
+// cell's neighbors: the board is interned into a Node, padded so the
+// centered half-size square Step1 returns still covers the whole board,
+// stepped by Step1 (not Step — Step
+// advances by 2^(Level-2) generations, Hashlife's usual macro-jump
+// optimization, which would silently desync g.generation from the real
+// elapsed count), and read back into nextState. g.world persists across
+// calls (and is only rebuilt when the rule changes), so its hash-consing
+// table and memo caches keep paying off as the board evolves: interning a
+// generation that reproduces an already-seen subpattern returns the same
+// *Node, and Step1 skips recomputing it.
+func (g *gameOfLife) stepHashlife() {
+	built := g.world.FromCells(g.currentState)
+	stepped := g.world.Step1(g.world.Pad(built))
+
+	for i := 0; i < g.size; i++ {
+		for j := 0; j < g.size; j++ {
+			g.nextState[i][j] = stepped.Cell(i, j)
+		}
+	}
+}
+
+// stepBitboard fills nextState with the board advanced exactly one
+// generation via internal/bitboard: a bit-packed baseline with no
+// memoization, kept around so Hashlife's speed on repetitive boards can be
+// benchmarked against something straightforward. Unlike g.world, a bitboard
+// has no cross-generation state worth keeping, so it's built fresh here
+// rather than stored on g.
+func (g *gameOfLife) stepBitboard() {
+	built := bitboard.FromCells(g.currentState, g.size)
+	stepped := built.Step(bitboard.Rule(g.rule))
+	stepped.Into(g.nextState)
+}
+
+// FastForward advances the board using hashlife's Step (not Step1) —
+// the macro-jump that is the whole reason Hashlife exists, skipping
+// 2^(Level-2) generations in a single call rather than one. It returns
+// the number of generations actually skipped, or 0 if it didn't run
+// (wrap mode and the bitboard engine have no macro-jump to offer; only
+// the bounded hashlife topology does).
+//
+// This is deliberately a separate, explicitly-named control rather than
+// something calculateNextState reaches for automatically: letting the
+// per-tick step silently jump a variable, content-dependent number of
+// generations is exactly the bug fixed by stepHashlife switching from
+// Step to Step1 (see stepHashlife) — g.generation, Undo/Redo, and
+// JumpToGeneration all depend on every calculateNextState call advancing
+// by exactly 1. FastForward keeps that contract by recording the whole
+// jump as a single history entry: Undo reverts it in one step, but the
+// individual generations skipped over are not individually visitable via
+// Undo/Redo/JumpToGeneration, the way stepped-to generations are.
+func (g *gameOfLife) FastForward() int {
+	if g.wrap || g.engine != EngineHashlife {
+		return 0
+	}
+
+	g.recordHistory()
+	if g.pendingRule != nil {
+		g.rule = *g.pendingRule
+		g.pendingRule = nil
+		g.world = hashlife.NewWorld(hashlife.Rule(g.rule))
+	}
+
+	built := g.world.Pad(g.world.FromCells(g.currentState))
+	jump := 1 << (built.Level() - 2)
+	stepped := g.world.Step(built)
+
+	for i := 0; i < g.size; i++ {
+		for j := 0; j < g.size; j++ {
+			g.currentState[i][j] = stepped.Cell(i, j)
+		}
+	}
+	g.generation += jump
+	return jump
+}
+
+// stepToroidal fills nextState with the board's successor, treating the
+// edges as wrapping to the opposite side.
+func (g *gameOfLife) stepToroidal() {
+	for i := 0; i < g.size; i++ {
+		for j := 0; j < g.size; j++ {
+			n := g.countNeighborsWrapped(i, j)
+			if g.currentState[i][j] {
+				g.nextState[i][j] = g.rule.Survive[n]
+			} else {
+				g.nextState[i][j] = g.rule.Birth[n]
+			}
+		}
+	}
+}
+
+// countNeighborsWrapped counts (x, y)'s eight neighbors on a torus, so a
+// cell on one edge sees the opposite edge as adjacent.
+func (g *gameOfLife) countNeighborsWrapped(x, y int) int {
+	n := 0
+	for di := -1; di <= 1; di++ {
+		for dj := -1; dj <= 1; dj++ {
+			if di == 0 && dj == 0 {
+				continue
+			}
+			i := (x + di + g.size) % g.size
+			j := (y + dj + g.size) % g.size
+			if g.currentState[i][j] {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// generationSnapshot is a bit-packed copy of a board at one point in
+// history: size*size cells packed 64 to a word instead of a size*size
+// bool slice, so keeping historyDepth of them around stays cheap. rule is
+// carried alongside so Undo/Redo across a rule change restores the rule
+// that was active for that generation, not whatever is active now.
+type generationSnapshot struct {
+	size int
+	gen  int
+	rule Rule
+	bits []uint64
+}
+
+// packGeneration bit-packs cells (a size x size board) tagged with gen and rule.
+func packGeneration(cells [][]bool, size, gen int, rule Rule) generationSnapshot {
+	s := generationSnapshot{size: size, gen: gen, rule: rule, bits: make([]uint64, (size*size+63)/64)}
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			if cells[x][y] {
+				i := x*size + y
+				s.bits[i/64] |= 1 << uint(i%64)
+			}
+		}
+	}
+	return s
+}
+
+// unpackInto writes s back into cells, which must already be s.size x
+// s.size.
+func (s generationSnapshot) unpackInto(cells [][]bool) {
+	for x := 0; x < s.size; x++ {
+		for y := 0; y < s.size; y++ {
+			i := x*s.size + y
+			cells[x][y] = s.bits[i/64]&(1<<uint(i%64)) != 0
+		}
+	}
+}
+
+// recordHistory snapshots the current board before a mutation (a
+// generation step or a manual edit) onto the undo history, evicting the
+// oldest entry once there are more than historyDepth, and discards any
+// pending redo: a fresh change after an Undo abandons the timeline redo
+// would have replayed.
+func (g *gameOfLife) recordHistory() {
+	g.history = append(g.history, packGeneration(g.currentState, g.size, g.generation, g.rule))
+	if len(g.history) > g.historyDepth {
+		g.history = g.history[len(g.history)-g.historyDepth:]
+	}
+	g.redo = g.redo[:0]
+}
+
+// restore replaces the board (and, if it differs, the rule and the
+// hashlife world built for it) with snap, reallocating currentState/
+// nextState first if the board has since been resized.
+func (g *gameOfLife) restore(snap generationSnapshot) {
+	if snap.size != g.size {
+		g.size = snap.size
+		g.currentState = make([][]bool, g.size)
+		g.nextState = make([][]bool, g.size)
+		for i := range g.currentState {
+			g.currentState[i] = make([]bool, g.size)
+			g.nextState[i] = make([]bool, g.size)
+		}
+	}
+	snap.unpackInto(g.currentState)
+	g.generation = snap.gen
+	if snap.rule != g.rule {
+		g.rule = snap.rule
+		g.pendingRule = nil
+		g.world = hashlife.NewWorld(hashlife.Rule(g.rule))
+	}
+}
+
+// Undo reverts the board to its state before the most recently recorded
+// change (a generation step or a manual edit) and reports whether there
+// was one to revert to.
+func (g *gameOfLife) Undo() bool {
+	if len(g.history) == 0 {
+		return false
+	}
+	prev := g.history[len(g.history)-1]
+	g.history = g.history[:len(g.history)-1]
+
+	g.redo = append(g.redo, packGeneration(g.currentState, g.size, g.generation, g.rule))
+	if len(g.redo) > g.historyDepth {
+		g.redo = g.redo[len(g.redo)-g.historyDepth:]
+	}
+
+	g.restore(prev)
+	return true
+}
+
+// Redo reapplies the most recent change undone by Undo and reports
+// whether there was one to reapply.
+func (g *gameOfLife) Redo() bool {
+	if len(g.redo) == 0 {
+		return false
+	}
+	next := g.redo[len(g.redo)-1]
+	g.redo = g.redo[:len(g.redo)-1]
+
+	g.history = append(g.history, packGeneration(g.currentState, g.size, g.generation, g.rule))
+	if len(g.history) > g.historyDepth {
+		g.history = g.history[len(g.history)-g.historyDepth:]
+	}
+
+	g.restore(next)
+	return true
+}
+
+// JumpToGeneration rewinds via Undo or advances via Redo/calculateNextState
+// until g.generation == n (clamped to >= 0), replaying remembered states
+// where possible and only simulating fresh generations when n is beyond
+// anything Redo can replay. Rewinding stops early if n is older than
+// historyDepth has remembered. Forward simulation is capped at
+// maxJumpSteps so a mistyped generation number can't hang the UI.
+func (g *gameOfLife) JumpToGeneration(n int) {
+	if n < 0 {
+		n = 0
+	}
+	for g.generation > n && g.Undo() {
+	}
+	for steps := 0; g.generation < n && steps < maxJumpSteps; steps++ {
+		if !g.Redo() {
+			g.calculateNextState()
+		}
+	}
+}
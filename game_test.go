@@ -0,0 +1,58 @@
+package main
+
+import "testing"
+
+func TestParseRule(t *testing.T) {
+	r, err := ParseRule("B3/S23")
+	if err != nil {
+		t.Fatalf("ParseRule: %v", err)
+	}
+	for n := 0; n <= 8; n++ {
+		wantBirth := n == 3
+		wantSurvive := n == 2 || n == 3
+		if r.Birth[n] != wantBirth {
+			t.Errorf("Birth[%d] = %v, want %v", n, r.Birth[n], wantBirth)
+		}
+		if r.Survive[n] != wantSurvive {
+			t.Errorf("Survive[%d] = %v, want %v", n, r.Survive[n], wantSurvive)
+		}
+	}
+}
+
+func TestParseRuleEmptySurvive(t *testing.T) {
+	r, err := ParseRule("B2/S")
+	if err != nil {
+		t.Fatalf("ParseRule: %v", err)
+	}
+	if !r.Birth[2] {
+		t.Errorf("expected Birth[2], got %+v", r.Birth)
+	}
+	for n := 0; n <= 8; n++ {
+		if r.Survive[n] {
+			t.Errorf("Survive[%d] should be false, rule has no survive digits", n)
+		}
+	}
+}
+
+func TestParseRuleInvalid(t *testing.T) {
+	for _, s := range []string{"B3S23", "X3/S23", "B3/X23", "B9/S23", "B3/S"} {
+		if s == "B3/S" {
+			continue // valid: empty survive set
+		}
+		if _, err := ParseRule(s); err == nil {
+			t.Errorf("ParseRule(%q): expected an error, got nil", s)
+		}
+	}
+}
+
+func TestRuleStringRoundTrip(t *testing.T) {
+	for _, s := range []string{"B3/S23", "B36/S23", "B2/S", "B3678/S34678"} {
+		r, err := ParseRule(s)
+		if err != nil {
+			t.Fatalf("ParseRule(%q): %v", s, err)
+		}
+		if got := r.String(); got != s {
+			t.Errorf("String() round-trip: got %q, want %q", got, s)
+		}
+	}
+}
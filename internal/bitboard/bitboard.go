@@ -0,0 +1,213 @@
+// Package bitboard is a bit-packed baseline engine for benchmarking against
+// internal/hashlife: each row is a []uint64 (64 columns per word) instead
+// of one bool per cell, and a Step counts all eight neighbors for every
+// column in a word at once with bitwise "sideways adder" logic (the same
+// half/full-adder trick a hardware adder uses, just run across 64 lanes in
+// parallel) instead of looping cell by cell. Unlike hashlife it has no
+// quadtree memoization, so it always costs O(board area) per step — the
+// point of keeping it around is to have something simple to compare
+// Hashlife's asymptotic win against.
+package bitboard
+
+// Rule is the neighbor-count transition Step uses, expressed the same way
+// as the desktop game's Rule type but kept local so this package has no
+// dependency on the pixel/glfw frontend (mirrors hashlife.Rule).
+type Rule struct {
+	Birth   [9]bool
+	Survive [9]bool
+}
+
+// ConwayLife is the standard B3/S23 rule. A Board carries no rule of its
+// own: Step applies exactly the Rule it is passed, and the zero Rule is not
+// replaced by ConwayLife.
+var ConwayLife = Rule{
+	Birth:   [9]bool{false, false, false, true, false, false, false, false, false},
+	Survive: [9]bool{false, false, true, true, false, false, false, false, false},
+}
+
+// Board is a bounded (non-toroidal) width x height board, bit-packed one
+// bit per cell: column x of row y is bit (x%64) of rows[y][x/64]. Bits at
+// or beyond Width in a row's last word are always kept 0 so shifts never
+// leak garbage columns back onto the board.
+type Board struct {
+	Width, Height int
+	words         int // words per row, ceil(Width/64)
+	rows          [][]uint64
+}
+
+// NewBoard allocates an empty width x height board.
+func NewBoard(width, height int) *Board {
+	b := &Board{Width: width, Height: height, words: (width + 63) / 64}
+	b.rows = make([][]uint64, height)
+	for y := range b.rows {
+		b.rows[y] = make([]uint64, b.words)
+	}
+	return b
+}
+
+// Set sets or clears the cell at (x, y).
+func (b *Board) Set(x, y int, alive bool) {
+	if alive {
+		b.rows[y][x/64] |= 1 << uint(x%64)
+	} else {
+		b.rows[y][x/64] &^= 1 << uint(x%64)
+	}
+}
+
+// Cell reports whether (x, y) is alive.
+func (b *Board) Cell(x, y int) bool {
+	return b.rows[y][x/64]&(1<<uint(x%64)) != 0
+}
+
+// FromCells packs a size x size board indexed [x][y] (gameOfLife's
+// currentState layout) into a new Board.
+func FromCells(cells [][]bool, size int) *Board {
+	b := NewBoard(size, size)
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			if cells[x][y] {
+				b.Set(x, y, true)
+			}
+		}
+	}
+	return b
+}
+
+// Into unpacks b back into cells, which must already be b.Width x b.Height.
+func (b *Board) Into(cells [][]bool) {
+	for x := 0; x < b.Width; x++ {
+		for y := 0; y < b.Height; y++ {
+			cells[x][y] = b.Cell(x, y)
+		}
+	}
+}
+
+// lastWordMask has the low bits set that correspond to valid columns in a
+// row's final word (all of them, if Width is a multiple of 64).
+func (b *Board) lastWordMask() uint64 {
+	valid := b.Width % 64
+	if valid == 0 {
+		return ^uint64(0)
+	}
+	return (uint64(1) << uint(valid)) - 1
+}
+
+// shiftWest returns row shifted one column west: out[x] = row[x+1], i.e.
+// each column takes on its east neighbor's value, with 0 shifted in past
+// the last column (the board is bounded, not toroidal).
+func shiftWest(row []uint64, words int, lastMask uint64) []uint64 {
+	out := make([]uint64, words)
+	for w := 0; w < words; w++ {
+		out[w] = row[w] >> 1
+		if w+1 < words {
+			out[w] |= (row[w+1] & 1) << 63
+		}
+	}
+	out[words-1] &= lastMask
+	return out
+}
+
+// shiftEast returns row shifted one column east: out[x] = row[x-1], i.e.
+// each column takes on its west neighbor's value, with 0 shifted in before
+// column 0.
+func shiftEast(row []uint64, words int, lastMask uint64) []uint64 {
+	out := make([]uint64, words)
+	for w := 0; w < words; w++ {
+		out[w] = row[w] << 1
+		if w > 0 {
+			out[w] |= row[w-1] >> 63
+		}
+	}
+	out[words-1] &= lastMask
+	return out
+}
+
+// addBit adds the single-bit plane b into the 4-bit counter planes c (c[0]
+// is the LSB), propagating carries elementwise the same way a ripple-carry
+// adder propagates them bit by bit — since each uint64 lane is an
+// independent column, this sums all 64 columns' counts in parallel.
+func addBit(c *[4][]uint64, words int, b []uint64) {
+	carry := b
+	for bit := 0; bit < 4; bit++ {
+		sum := make([]uint64, words)
+		nextCarry := make([]uint64, words)
+		for w := 0; w < words; w++ {
+			sum[w] = c[bit][w] ^ carry[w]
+			nextCarry[w] = c[bit][w] & carry[w]
+		}
+		c[bit] = sum
+		carry = nextCarry
+	}
+}
+
+// isCount returns, for each column, whether its neighbor count (given as
+// 4-bit planes c) equals n.
+func isCount(n int, c [4][]uint64, words int) []uint64 {
+	out := make([]uint64, words)
+	for w := 0; w < words; w++ {
+		v := ^uint64(0)
+		for bit := 0; bit < 4; bit++ {
+			if n&(1<<uint(bit)) != 0 {
+				v &= c[bit][w]
+			} else {
+				v &= ^c[bit][w]
+			}
+		}
+		out[w] = v
+	}
+	return out
+}
+
+// Step advances b by exactly one generation under rule, returning a new
+// Board (b is left unmodified).
+func (b *Board) Step(rule Rule) *Board {
+	lastMask := b.lastWordMask()
+	zero := make([]uint64, b.words)
+
+	rowAt := func(y int) []uint64 {
+		if y < 0 || y >= b.Height {
+			return zero
+		}
+		return b.rows[y]
+	}
+
+	out := NewBoard(b.Width, b.Height)
+	for y := 0; y < b.Height; y++ {
+		north, self, south := rowAt(y-1), rowAt(y), rowAt(y+1)
+
+		var count [4][]uint64
+		for i := range count {
+			count[i] = make([]uint64, b.words)
+		}
+		addBit(&count, b.words, shiftWest(north, b.words, lastMask))
+		addBit(&count, b.words, north)
+		addBit(&count, b.words, shiftEast(north, b.words, lastMask))
+		addBit(&count, b.words, shiftWest(self, b.words, lastMask))
+		addBit(&count, b.words, shiftEast(self, b.words, lastMask))
+		addBit(&count, b.words, shiftWest(south, b.words, lastMask))
+		addBit(&count, b.words, south)
+		addBit(&count, b.words, shiftEast(south, b.words, lastMask))
+
+		var birth, survive []uint64 = make([]uint64, b.words), make([]uint64, b.words)
+		for n := 0; n <= 8; n++ {
+			mask := isCount(n, count, b.words)
+			if rule.Birth[n] {
+				for w := range birth {
+					birth[w] |= mask[w]
+				}
+			}
+			if rule.Survive[n] {
+				for w := range survive {
+					survive[w] |= mask[w]
+				}
+			}
+		}
+
+		nextRow := out.rows[y]
+		for w := 0; w < b.words; w++ {
+			nextRow[w] = (self[w] & survive[w]) | (^self[w] & birth[w])
+		}
+		nextRow[b.words-1] &= lastMask
+	}
+	return out
+}
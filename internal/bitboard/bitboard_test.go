@@ -0,0 +1,96 @@
+package bitboard
+
+import "testing"
+
+func blinkerCells(size int) [][]bool {
+	cells := make([][]bool, size)
+	for x := range cells {
+		cells[x] = make([]bool, size)
+	}
+	cells[2][4], cells[3][4], cells[4][4] = true, true, true
+	return cells
+}
+
+func TestStepBlinkerOscillates(t *testing.T) {
+	cells := blinkerCells(8)
+	b := FromCells(cells, 8)
+
+	gen1 := b.Step(ConwayLife)
+	if !(gen1.Cell(3, 3) && gen1.Cell(3, 4) && gen1.Cell(3, 5)) {
+		t.Fatalf("expected a vertical blinker at x=3 after one step")
+	}
+	if gen1.Cell(2, 4) || gen1.Cell(4, 4) {
+		t.Errorf("expected the original horizontal cells to be dead after one step")
+	}
+
+	gen2 := gen1.Step(ConwayLife)
+	if !(gen2.Cell(2, 4) && gen2.Cell(3, 4) && gen2.Cell(4, 4)) {
+		t.Fatalf("expected the blinker back to horizontal after two steps")
+	}
+}
+
+func TestStepEmptyBoardStaysEmpty(t *testing.T) {
+	b := NewBoard(70, 70)
+	stepped := b.Step(ConwayLife)
+	for x := 0; x < 70; x++ {
+		for y := 0; y < 70; y++ {
+			if stepped.Cell(x, y) {
+				t.Fatalf("expected an empty board to stay empty, found a live cell at (%d,%d)", x, y)
+			}
+		}
+	}
+}
+
+// TestStepAcrossWordBoundary exercises a board wider than 64 columns (two
+// words per row), with a blinker straddling the column-63/64 boundary, to
+// make sure shiftWest/shiftEast carry bits across words correctly.
+func TestStepAcrossWordBoundary(t *testing.T) {
+	size := 70
+	cells := make([][]bool, size)
+	for x := range cells {
+		cells[x] = make([]bool, size)
+	}
+	cells[62][10], cells[63][10], cells[64][10] = true, true, true
+	b := FromCells(cells, size)
+
+	gen1 := b.Step(ConwayLife)
+	if !(gen1.Cell(63, 9) && gen1.Cell(63, 10) && gen1.Cell(63, 11)) {
+		t.Fatalf("expected the blinker straddling the word boundary to flip to vertical")
+	}
+}
+
+func TestStepRespectsBoundedEdges(t *testing.T) {
+	// A single live cell in a corner has too few neighbors to survive or
+	// spawn anything, and a bounded board must not wrap it to the far edge.
+	cells := blinkerCells(8)
+	cells[0][0] = true
+	b := FromCells(cells, 8)
+	gen1 := b.Step(ConwayLife)
+	if gen1.Cell(0, 0) || gen1.Cell(7, 7) {
+		t.Errorf("a lone corner cell should die, and must not wrap to the opposite edge")
+	}
+}
+
+func TestStepHighLifeBirth(t *testing.T) {
+	// HighLife (B36/S23): two parallel 3-cell lines with a one-cell gap
+	// give the gap's center cell 6 neighbors, so it is born under B6 where
+	// B3/S23 would leave it dead, distinguishing the rule's Birth[6]
+	// handling from Conway's.
+	size := 8
+	cells := make([][]bool, size)
+	for x := range cells {
+		cells[x] = make([]bool, size)
+	}
+	for _, c := range [][2]int{{2, 2}, {2, 3}, {2, 4}, {4, 2}, {4, 3}, {4, 4}} {
+		cells[c[0]][c[1]] = true
+	}
+	b := FromCells(cells, size)
+	highlife := Rule{
+		Birth:   [9]bool{false, false, false, true, false, false, true, false, false},
+		Survive: [9]bool{false, false, true, true, false, false, false, false, false},
+	}
+	gen1 := b.Step(highlife)
+	if !gen1.Cell(3, 3) {
+		t.Errorf("expected HighLife's B6 rule to birth a cell at (3,3)")
+	}
+}
@@ -0,0 +1,372 @@
+// Package hashlife implements Gosper's Hashlife algorithm: the universe is
+// a canonicalized quadtree of Nodes, interned in a hash-consing table so
+// identical subpatterns share memory, and World.Step is memoized on the
+// node pointer so repeated or still-life regions are never recomputed.
+package hashlife
+
+// Rule is the neighbor-count transition World.Step uses for its level-2
+// base case, expressed the same way as the desktop game's Rule type but
+// kept local so this package has no dependency on the pixel/glfw frontend.
+type Rule struct {
+	Birth   [9]bool
+	Survive [9]bool
+}
+
+// ConwayLife is the standard B3/S23 rule, used when a World is built with
+// the zero Rule.
+var ConwayLife = Rule{
+	Birth:   [9]bool{false, false, false, true, false, false, false, false, false},
+	Survive: [9]bool{false, false, true, true, false, false, false, false, false},
+}
+
+// Node is one quadtree node. Level 0 is a single cell; a level-L node
+// (L>0) is the join of four level-(L-1) children spanning a 2^L x 2^L
+// square. Nodes are immutable once interned, so equal subpatterns are
+// always the same *Node and can be compared by pointer.
+type Node struct {
+	level          uint8
+	population     uint64
+	hash           uint64
+	nw, ne, sw, se *Node
+	alive          bool // only meaningful when level == 0
+}
+
+// Level reports the node's quadtree level (side length 2^Level).
+func (n *Node) Level() uint8 { return n.level }
+
+// Population reports the number of live cells under n.
+func (n *Node) Population() uint64 { return n.population }
+
+// World owns a World's hash-consing table and Step/Step1 memo caches, and
+// the rule new level-2 base cases are evaluated against.
+type World struct {
+	rule                Rule
+	nodes               map[uint64][]*Node
+	memo                map[*Node]*Node // Step's memo: n -> n advanced 2^(n.Level()-2) generations
+	memo1               map[*Node]*Node // Step1's memo: n -> n advanced exactly 1 generation
+	empty               []*Node         // empty[L] is the canonical empty node at level L
+	deadLeaf, aliveLeaf *Node
+}
+
+// NewWorld creates a World for rule. The zero Rule is treated as ConwayLife.
+func NewWorld(rule Rule) *World {
+	if rule == (Rule{}) {
+		rule = ConwayLife
+	}
+	w := &World{
+		rule:  rule,
+		nodes: make(map[uint64][]*Node),
+		memo:  make(map[*Node]*Node),
+		memo1: make(map[*Node]*Node),
+	}
+	w.deadLeaf = w.intern(&Node{level: 0, alive: false, hash: hashLeaf(false)})
+	w.aliveLeaf = w.intern(&Node{level: 0, alive: true, population: 1, hash: hashLeaf(true)})
+	w.empty = []*Node{w.deadLeaf}
+	return w
+}
+
+// Leaf returns the canonical level-0 node for alive.
+func (w *World) Leaf(alive bool) *Node {
+	if alive {
+		return w.aliveLeaf
+	}
+	return w.deadLeaf
+}
+
+// Empty returns the canonical all-dead node at level.
+func (w *World) Empty(level uint8) *Node {
+	for uint8(len(w.empty)) <= level {
+		e := w.empty[len(w.empty)-1]
+		w.empty = append(w.empty, w.Join(e, e, e, e))
+	}
+	return w.empty[level]
+}
+
+// Join interns the node formed by combining four equal-level children into
+// one node one level up.
+func (w *World) Join(nw, ne, sw, se *Node) *Node {
+	if nw.level != ne.level || nw.level != sw.level || nw.level != se.level {
+		panic("hashlife: Join requires four children of equal level")
+	}
+	n := &Node{
+		level:      nw.level + 1,
+		population: nw.population + ne.population + sw.population + se.population,
+		nw:         nw, ne: ne, sw: sw, se: se,
+		hash: hashInternal(nw, ne, sw, se),
+	}
+	return w.intern(n)
+}
+
+func (w *World) intern(n *Node) *Node {
+	for _, existing := range w.nodes[n.hash] {
+		if nodeEqual(existing, n) {
+			return existing
+		}
+	}
+	w.nodes[n.hash] = append(w.nodes[n.hash], n)
+	return n
+}
+
+func nodeEqual(a, b *Node) bool {
+	if a.level != b.level {
+		return false
+	}
+	if a.level == 0 {
+		return a.alive == b.alive
+	}
+	return a.nw == b.nw && a.ne == b.ne && a.sw == b.sw && a.se == b.se
+}
+
+func hashLeaf(alive bool) uint64 {
+	if alive {
+		return 1
+	}
+	return 0
+}
+
+// hashInternal combines four child hashes with an FNV-1a style mix; it
+// only needs to distinguish nodes, not resist adversarial input.
+func hashInternal(nw, ne, sw, se *Node) uint64 {
+	const offset, prime = 1469598103934665603, 1099511628211
+	h := uint64(offset)
+	for _, c := range [4]*Node{nw, ne, sw, se} {
+		h ^= c.hash
+		h *= prime
+	}
+	return h
+}
+
+// Step advances the center 2^(n.Level()-1) square of n by 2^(n.Level()-2)
+// generations — not one — and returns it as a node of level n.Level()-1.
+// This macro-jump is the entire performance trick behind Hashlife: larger,
+// more-redundant patterns leap further per call instead of paying per-cell
+// cost for every elapsed generation. n must have level >= 2. Results are
+// memoized per node pointer, so advancing a pattern with large still or
+// periodic regions revisits almost no work on repeat calls.
+//
+// When exactly one generation is needed regardless of n's level — a
+// single-step hotkey, an exact generation counter, Undo/Redo — use Step1
+// instead.
+func (w *World) Step(n *Node) *Node {
+	if n.level < 2 {
+		panic("hashlife: Step requires level >= 2")
+	}
+	if n.population == 0 {
+		return w.Empty(n.level - 1)
+	}
+	if result, ok := w.memo[n]; ok {
+		return result
+	}
+
+	var result *Node
+	if n.level == 2 {
+		result = w.stepBase(n)
+	} else {
+		result = w.stepRecursive(n)
+	}
+
+	w.memo[n] = result
+	return result
+}
+
+// stepBase handles the 4x4 case directly: it has exactly the neighborhood
+// needed to compute the next state of the center 2x2 using w.rule.
+func (w *World) stepBase(n *Node) *Node {
+	var grid [4][4]bool
+	grid[0][0], grid[1][0] = n.nw.nw.alive, n.nw.ne.alive
+	grid[0][1], grid[1][1] = n.nw.sw.alive, n.nw.se.alive
+	grid[2][0], grid[3][0] = n.ne.nw.alive, n.ne.ne.alive
+	grid[2][1], grid[3][1] = n.ne.sw.alive, n.ne.se.alive
+	grid[0][2], grid[1][2] = n.sw.nw.alive, n.sw.ne.alive
+	grid[0][3], grid[1][3] = n.sw.sw.alive, n.sw.se.alive
+	grid[2][2], grid[3][2] = n.se.nw.alive, n.se.ne.alive
+	grid[2][3], grid[3][3] = n.se.sw.alive, n.se.se.alive
+
+	next := func(x, y int) bool {
+		count := 0
+		for dx := -1; dx <= 1; dx++ {
+			for dy := -1; dy <= 1; dy++ {
+				if dx == 0 && dy == 0 {
+					continue
+				}
+				if grid[x+dx][y+dy] {
+					count++
+				}
+			}
+		}
+		if grid[x][y] {
+			return w.rule.Survive[count]
+		}
+		return w.rule.Birth[count]
+	}
+
+	return w.Join(
+		w.Leaf(next(1, 1)), w.Leaf(next(2, 1)),
+		w.Leaf(next(1, 2)), w.Leaf(next(2, 2)),
+	)
+}
+
+// stepRecursive implements the classic Hashlife combine: nine overlapping
+// level-(L-1) subsquares are each advanced a half-step, recombined into
+// four level-(L-1) quadrants, and those are advanced a second half-step
+// and joined into the final level-(L-1) result.
+func (w *World) stepRecursive(n *Node) *Node {
+	nw, ne, sw, se := n.nw, n.ne, n.sw, n.se
+
+	n00, n02, n20, n22 := nw, ne, sw, se
+	n01 := w.Join(nw.ne, ne.nw, nw.se, ne.sw)
+	n10 := w.Join(nw.sw, nw.se, sw.nw, sw.ne)
+	n11 := w.Join(nw.se, ne.sw, sw.ne, se.nw)
+	n12 := w.Join(ne.sw, ne.se, se.nw, se.ne)
+	n21 := w.Join(sw.ne, se.nw, sw.se, se.sw)
+
+	r00, r01, r02 := w.Step(n00), w.Step(n01), w.Step(n02)
+	r10, r11, r12 := w.Step(n10), w.Step(n11), w.Step(n12)
+	r20, r21, r22 := w.Step(n20), w.Step(n21), w.Step(n22)
+
+	qnw := w.Join(r00, r01, r10, r11)
+	qne := w.Join(r01, r02, r11, r12)
+	qsw := w.Join(r10, r11, r20, r21)
+	qse := w.Join(r11, r12, r21, r22)
+
+	return w.Join(w.Step(qnw), w.Step(qne), w.Step(qsw), w.Step(qse))
+}
+
+// center returns the level-(n.Level()-1) square centered within n, with no
+// change in generation — the inverse of Pad's embedding. Step1 uses this in
+// place of stepRecursive's second Step stage, since that second stage is
+// what doubles the advance (see Step1).
+func (w *World) center(n *Node) *Node {
+	return w.Join(n.nw.se, n.ne.sw, n.sw.ne, n.se.nw)
+}
+
+// Step1 advances the center 2^(n.Level()-1) square of n by exactly one
+// generation and returns it as a node of level n.Level()-1, regardless of
+// n's level. n must have level >= 2. Results above level 2 are memoized per
+// node pointer the same way Step's are, in a separate cache, since a
+// *Node's Step and Step1 results differ; level-2 nodes are recomputed by
+// stepBase on every call.
+//
+// stepRecursive (Step's >2 path) advances nine overlapping subsquares by
+// Step (2^(Level-3) generations) and combines them into four quadrants,
+// then advances those quadrants by Step a second time (another
+// 2^(Level-3)) — that second stage is what makes Step's total 2^(Level-2)
+// instead of 2^(Level-3). Step1 uses the same nine-subsquare combine but
+// advances the subsquares with Step1 (exactly one generation), and
+// replaces the second stage with center — a pure crop, no rule applied —
+// so the result is always exactly one generation ahead, at every level.
+func (w *World) Step1(n *Node) *Node {
+	if n.level < 2 {
+		panic("hashlife: Step1 requires level >= 2")
+	}
+	if n.population == 0 {
+		return w.Empty(n.level - 1)
+	}
+	if n.level == 2 {
+		return w.stepBase(n)
+	}
+	if result, ok := w.memo1[n]; ok {
+		return result
+	}
+
+	nw, ne, sw, se := n.nw, n.ne, n.sw, n.se
+
+	n00, n02, n20, n22 := nw, ne, sw, se
+	n01 := w.Join(nw.ne, ne.nw, nw.se, ne.sw)
+	n10 := w.Join(nw.sw, nw.se, sw.nw, sw.ne)
+	n11 := w.Join(nw.se, ne.sw, sw.ne, se.nw)
+	n12 := w.Join(ne.sw, ne.se, se.nw, se.ne)
+	n21 := w.Join(sw.ne, se.nw, sw.se, se.sw)
+
+	r00, r01, r02 := w.Step1(n00), w.Step1(n01), w.Step1(n02)
+	r10, r11, r12 := w.Step1(n10), w.Step1(n11), w.Step1(n12)
+	r20, r21, r22 := w.Step1(n20), w.Step1(n21), w.Step1(n22)
+
+	qnw := w.Join(r00, r01, r10, r11)
+	qne := w.Join(r01, r02, r11, r12)
+	qsw := w.Join(r10, r11, r20, r21)
+	qse := w.Join(r11, r12, r21, r22)
+
+	result := w.Join(w.center(qnw), w.center(qne), w.center(qsw), w.center(qse))
+	w.memo1[n] = result
+	return result
+}
+
+// Pad embeds n, centered, in a node one level larger with an empty border.
+// Stepping the padded node returns a result the same size as n, which is
+// the usual way to advance a whole board without losing its edges: Step
+// and Step1 each return only the centered half-size square of their input.
+// n must have level >= 1.
+func (w *World) Pad(n *Node) *Node {
+	if n.level < 1 {
+		panic("hashlife: Pad requires level >= 1")
+	}
+	e := w.Empty(n.level - 1)
+	return w.Join(
+		w.Join(e, e, e, n.nw),
+		w.Join(e, e, n.ne, e),
+		w.Join(e, n.sw, e, e),
+		w.Join(n.se, e, e, e),
+	)
+}
+
+// Cell reports whether the cell at local coordinates (x, y) within n's
+// 2^Level() x 2^Level() square is alive. x and y must each be in
+// [0, 1<<Level()); behavior is undefined otherwise.
+func (n *Node) Cell(x, y int) bool {
+	if n.level == 0 {
+		return n.alive
+	}
+	half := 1 << (n.level - 1)
+	switch {
+	case x < half && y < half:
+		return n.nw.Cell(x, y)
+	case x >= half && y < half:
+		return n.ne.Cell(x-half, y)
+	case x < half && y >= half:
+		return n.sw.Cell(x, y-half)
+	default:
+		return n.se.Cell(x-half, y-half)
+	}
+}
+
+// FromCells builds a quadtree for a rectangular cells[x][y] grid, padded
+// with dead cells up to the next power-of-two square.
+func (w *World) FromCells(cells [][]bool) *Node {
+	width := len(cells)
+	height := 0
+	if width > 0 {
+		height = len(cells[0])
+	}
+	size := 1
+	for size < width || size < height {
+		size *= 2
+	}
+	if size < 1 {
+		size = 1
+	}
+
+	alive := func(x, y int) bool {
+		return x < width && y < len(cells[x]) && cells[x][y]
+	}
+
+	var build func(x0, y0, level int) *Node
+	build = func(x0, y0, level int) *Node {
+		if level == 0 {
+			return w.Leaf(alive(x0, y0))
+		}
+		half := 1 << (level - 1)
+		return w.Join(
+			build(x0, y0, level-1),
+			build(x0+half, y0, level-1),
+			build(x0, y0+half, level-1),
+			build(x0+half, y0+half, level-1),
+		)
+	}
+
+	level := 0
+	for 1<<level < size {
+		level++
+	}
+	return build(0, 0, level)
+}
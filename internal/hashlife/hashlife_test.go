@@ -0,0 +1,121 @@
+package hashlife
+
+import "testing"
+
+// blinkerCells returns an 8x8 grid with a horizontal 3-cell blinker, which
+// oscillates between horizontal and vertical every generation.
+func blinkerCells() [][]bool {
+	cells := make([][]bool, 8)
+	for x := range cells {
+		cells[x] = make([]bool, 8)
+	}
+	cells[2][4], cells[3][4], cells[4][4] = true, true, true
+	return cells
+}
+
+// aliveCells returns the coordinates of every live cell under n, relative
+// to n's own (0,0), so orientation checks don't need to know how Pad or
+// FromCells offset the pattern within the node.
+func aliveCells(n *Node) map[[2]int]bool {
+	out := make(map[[2]int]bool)
+	size := 1 << n.Level()
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			if n.Cell(x, y) {
+				out[[2]int{x, y}] = true
+			}
+		}
+	}
+	return out
+}
+
+// shape returns each live coordinate relative to the minimum live x and y,
+// so two patterns in the same orientation compare equal regardless of
+// where in the node they happen to sit.
+func shape(cells map[[2]int]bool) map[[2]int]bool {
+	if len(cells) == 0 {
+		return cells
+	}
+	minX, minY := 1<<30, 1<<30
+	for c := range cells {
+		if c[0] < minX {
+			minX = c[0]
+		}
+		if c[1] < minY {
+			minY = c[1]
+		}
+	}
+	out := make(map[[2]int]bool, len(cells))
+	for c := range cells {
+		out[[2]int{c[0] - minX, c[1] - minY}] = true
+	}
+	return out
+}
+
+func shapesEqual(a, b map[[2]int]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for c := range a {
+		if !b[c] {
+			return false
+		}
+	}
+	return true
+}
+
+func horizontalShape() map[[2]int]bool {
+	return map[[2]int]bool{{0, 0}: true, {1, 0}: true, {2, 0}: true}
+}
+
+func verticalShape() map[[2]int]bool {
+	return map[[2]int]bool{{0, 0}: true, {0, 1}: true, {0, 2}: true}
+}
+
+func TestStep1AdvancesExactlyOneGeneration(t *testing.T) {
+	w := NewWorld(ConwayLife)
+	built := w.FromCells(blinkerCells())
+
+	if got := shape(aliveCells(built)); !shapesEqual(got, horizontalShape()) {
+		t.Fatalf("seed pattern isn't the expected horizontal blinker: %v", got)
+	}
+
+	gen1 := w.Step1(w.Pad(built))
+	if got := shape(aliveCells(gen1)); !shapesEqual(got, verticalShape()) {
+		t.Errorf("after one Step1 call, expected the blinker to have flipped to vertical, got %v", got)
+	}
+
+	gen2 := w.Step1(w.Pad(gen1))
+	if got := shape(aliveCells(gen2)); !shapesEqual(got, horizontalShape()) {
+		t.Errorf("after two Step1 calls, expected the blinker back to horizontal, got %v", got)
+	}
+}
+
+func TestStepAdvancesByMacroJump(t *testing.T) {
+	w := NewWorld(ConwayLife)
+	// A level-4 node advances 2^(4-2) = 4 generations per Step call — an
+	// even number, so a period-2 blinker ends up back in its original
+	// orientation, not flipped, unlike the one-generation-per-call Step1.
+	built := w.Pad(w.FromCells(blinkerCells()))
+	for built.Level() < 4 {
+		built = w.Pad(built)
+	}
+
+	stepped := w.Step(built)
+	if got := shape(aliveCells(stepped)); !shapesEqual(got, horizontalShape()) {
+		t.Errorf("Step's macro-jump should advance this level-4 node by an even number of generations, leaving the blinker horizontal, got %v", got)
+	}
+}
+
+func TestStep1EmptyBoardStaysEmpty(t *testing.T) {
+	w := NewWorld(ConwayLife)
+	empty := make([][]bool, 8)
+	for x := range empty {
+		empty[x] = make([]bool, 8)
+	}
+	built := w.Pad(w.FromCells(empty))
+	stepped := w.Step1(built)
+	if stepped.Population() != 0 {
+		t.Errorf("expected an empty board to stay empty, got population %d", stepped.Population())
+	}
+}
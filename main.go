@@ -1,10 +1,19 @@
+//go:build !js
+// +build !js
+
+// Desktop entrypoint: renders the shared gameOfLife state (game.go) through
+// pixel/glfw. The WebAssembly entrypoint in main_js.go renders the same
+// state to an HTML5 canvas instead.
 package main
 
 import (
+	"flag"
 	"fmt"
 	"image/color"
 	"math"
-	"math/rand"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/faiface/pixel"
@@ -13,48 +22,15 @@ import (
 	"github.com/faiface/pixel/text"
 	"golang.org/x/image/colornames"
 	"golang.org/x/image/font/basicfont"
-)
 
-const (
-	cells          = 100
-	cellWidth      = 10
-	gameSize       = float64(cells * cellWidth)
-	initalCellsPct = 0.3
-	menuWidth      = 400
-	fps            = 30
-	disableEdges   = true
+	"gameOfLife/patterns"
 )
 
-var (
-	paused = false
+const (
+	gameSize  = float64(maxCells * cellWidth) // window is sized for the largest resizable board
+	menuWidth = 400
 )
 
-type gameOfLife struct {
-	currentState [][]bool
-	nextState    [][]bool
-	size         int
-}
-
-func (g *gameOfLife) initialize() {
-	g.currentState = make([][]bool, cells)
-	g.nextState = make([][]bool, cells)
-	for i := range g.currentState {
-		g.currentState[i] = make([]bool, cells)
-		g.nextState[i] = make([]bool, cells)
-	}
-
-	for i := 0; i < cells; i++ {
-		for j := 0; j < cells; j++ {
-			r := rand.Float32()
-			if r <= initalCellsPct {
-				g.currentState[i][j] = true
-			} else {
-				g.currentState[i][j] = false
-			}
-		}
-	}
-}
-
 func (g *gameOfLife) draw(imd *imdraw.IMDraw) {
 	imd.Clear()
 	for i, row := range g.currentState {
@@ -72,67 +48,7 @@ func (g *gameOfLife) draw(imd *imdraw.IMDraw) {
 	}
 }
 
-func (g *gameOfLife) checkNeighbors(x, y int) int {
-	n := 0
-	for i := x - 1; i <= x+1; i++ {
-		for j := y - 1; j <= y+1; j++ {
-			posX, posY := i, j
-
-			if disableEdges {
-				if posX < 0 || posX >= cells {
-					continue
-				}
-				if posY < 0 || posY >= cells {
-					continue
-				}
-			} else {
-
-				if posX == -1 {
-					posX = cells - 1
-				} else if posX == cells {
-					posX = 0
-				}
-
-				if posY == -1 {
-					posY = cells - 1
-				} else if posY == cells {
-					posY = 0
-				}
-			}
-
-			if posX == x && posY == y {
-				continue
-			}
-
-			if g.currentState[posX][posY] {
-				n++
-			}
-		}
-	}
-
-	return n
-}
-
-func (g *gameOfLife) calculateNextState() {
-	for i, row := range g.currentState {
-		for j, v := range row {
-			n := g.checkNeighbors(i, j)
-			g.nextState[i][j] = false
-			if v {
-				if n == 2 || n == 3 {
-					g.nextState[i][j] = true
-				}
-			} else {
-				if n == 3 {
-					g.nextState[i][j] = true
-				}
-			}
-		}
-	}
-	g.currentState, g.nextState = g.nextState, g.currentState
-}
-
-func drawMenu(imd *imdraw.IMDraw, w *pixelgl.Window) {
+func drawMenu(imd *imdraw.IMDraw, w *pixelgl.Window, g *gameOfLife) {
 
 	imd.Color = color.RGBA{0xb2, 0xeb, 0xf2, 0xff}
 	imd.Push(pixel.V(float64(gameSize), float64(0)))
@@ -159,6 +75,315 @@ func drawMenu(imd *imdraw.IMDraw, w *pixelgl.Window) {
 	fmt.Fprintln(pauseText, text)
 
 	pauseText.Draw(w, pixel.IM.Scaled(pauseText.Orig, textScale))
+
+	drawRuleMenu(imd, w, g)
+	drawSimMenu(imd, w, g)
+	drawHistoryMenu(imd, w, g)
+}
+
+// drawRuleMenu shows the active rulestring, the hotkeys to cycle the
+// built-in presets or type a custom one, and the in-progress input buffer
+// while ruleInputMode is active.
+func drawRuleMenu(imd *imdraw.IMDraw, w *pixelgl.Window, g *gameOfLife) {
+	atlas := text.NewAtlas(basicfont.Face7x13, text.ASCII)
+	ruleText := text.New(pixel.V(gameSize+20, 700), atlas)
+	ruleText.Color = colornames.Black
+
+	switch {
+	case g.ruleInputMode:
+		fmt.Fprintf(ruleText, "Rule: %s_\n", g.ruleInputText)
+		fmt.Fprintln(ruleText, "Enter to apply, Esc to cancel")
+	case g.ruleMismatch != "":
+		fmt.Fprintf(ruleText, "Pattern wants rule %s\n", g.ruleMismatch)
+		fmt.Fprintln(ruleText, "Y: switch   N: keep current")
+	case g.patternInputMode:
+		fmt.Fprintf(ruleText, "%s file: %s_\n", strings.Title(g.patternInputAction), g.patternInputText)
+		fmt.Fprintln(ruleText, "Enter to confirm, Esc to cancel")
+	default:
+		fmt.Fprintf(ruleText, "Rule: %s (%s)\n", g.rule, rulePresets[g.ruleIndex].Name)
+		fmt.Fprintln(ruleText, "R: next preset   /: type rule")
+		fmt.Fprintln(ruleText, "O: load pattern   X: save pattern")
+	}
+
+	ruleText.Draw(w, pixel.IM.Scaled(ruleText.Orig, 1.5))
+}
+
+// drawSimMenu shows the live topology, grid size, and simulation speed,
+// and the hotkeys that change them.
+func drawSimMenu(imd *imdraw.IMDraw, w *pixelgl.Window, g *gameOfLife) {
+	atlas := text.NewAtlas(basicfont.Face7x13, text.ASCII)
+	simText := text.New(pixel.V(gameSize+20, 500), atlas)
+	simText.Color = colornames.Black
+
+	topology := "bounded"
+	if g.wrap {
+		topology = "wrap"
+	}
+	fmt.Fprintf(simText, "Topology: %s   T: toggle\n", topology)
+	fmt.Fprintf(simText, "Engine: %s (-engine flag)\n", g.engine)
+	fmt.Fprintf(simText, "Grid: %dx%d   [ / ]: resize\n", g.size, g.size)
+	fmt.Fprintf(simText, "Speed: %d/s   , / . : slower/faster\n", g.ticksPerSecond)
+	fmt.Fprintln(simText, "S: single-step (while paused)")
+	if g.engine == EngineHashlife && !g.wrap {
+		fmt.Fprintln(simText, "F: fast-forward (macro-jump, skips fine history)")
+	}
+
+	simText.Draw(w, pixel.IM.Scaled(simText.Orig, 1.5))
+}
+
+// textEntryMode reports which of the mutually exclusive text-entry/prompt
+// modes is currently open ("rule", "pattern", "history", or "" if none), so
+// a handler whose hotkeys are plain letters/punctuation (T, [, ], ",", ".")
+// doesn't fire them as a side effect of ordinary typing into a different
+// handler's prompt — e.g. typing a filename like "oscillator.rle" into the
+// pattern-load prompt contains both "t" and ".".
+func textEntryMode(g *gameOfLife) string {
+	switch {
+	case g.ruleInputMode || g.ruleMismatch != "":
+		return "rule"
+	case g.patternInputMode:
+		return "pattern"
+	case g.historyInputMode:
+		return "history"
+	default:
+		return ""
+	}
+}
+
+// handleSimControls toggles wrap-around topology with T, resizes the grid
+// with [ and ], adjusts the simulation tick rate with , and ., advances
+// exactly one generation on S while paused, and macro-jumps ahead on F
+// while paused (see gameOfLife.FastForward).
+func handleSimControls(w *pixelgl.Window, g *gameOfLife) {
+	if textEntryMode(g) != "" {
+		return
+	}
+	if w.JustPressed(pixelgl.KeyT) {
+		g.wrap = !g.wrap
+	}
+	if w.JustPressed(pixelgl.KeyLeftBracket) {
+		g.Resize(g.size - cellSizeStep)
+	}
+	if w.JustPressed(pixelgl.KeyRightBracket) {
+		g.Resize(g.size + cellSizeStep)
+	}
+	if w.JustPressed(pixelgl.KeyComma) && g.ticksPerSecond > minTicksPerSecond {
+		g.ticksPerSecond -= ticksPerSecondStep
+		if g.ticksPerSecond < minTicksPerSecond {
+			g.ticksPerSecond = minTicksPerSecond
+		}
+	}
+	if w.JustPressed(pixelgl.KeyPeriod) && g.ticksPerSecond < maxTicksPerSecond {
+		g.ticksPerSecond += ticksPerSecondStep
+		if g.ticksPerSecond > maxTicksPerSecond {
+			g.ticksPerSecond = maxTicksPerSecond
+		}
+	}
+	if paused && w.JustPressed(pixelgl.KeyS) {
+		g.calculateNextState()
+	}
+	if paused && w.JustPressed(pixelgl.KeyF) {
+		g.FastForward()
+	}
+}
+
+// drawHistoryMenu shows the current generation and the undo/redo/jump
+// hotkeys, or the in-progress input buffer while historyInputMode is active.
+//
+// Undo/Redo use Z/V rather than the request's suggested ,/. since chunk0-5
+// already claimed ,/. for simulation speed.
+func drawHistoryMenu(imd *imdraw.IMDraw, w *pixelgl.Window, g *gameOfLife) {
+	atlas := text.NewAtlas(basicfont.Face7x13, text.ASCII)
+	histText := text.New(pixel.V(gameSize+20, 300), atlas)
+	histText.Color = colornames.Black
+
+	if g.historyInputMode {
+		fmt.Fprintf(histText, "Jump to gen: %s_\n", g.historyInputText)
+		fmt.Fprintln(histText, "Enter to jump, Esc to cancel")
+	} else {
+		fmt.Fprintf(histText, "Generation: %d\n", g.generation)
+		fmt.Fprintln(histText, "Z: undo   V: redo   G: jump to gen")
+	}
+
+	histText.Draw(w, pixel.IM.Scaled(histText.Orig, 1.5))
+}
+
+// handleHistoryControls undoes with Z, redoes with V, and opens a
+// "jump to generation" text prompt on G, mirroring the ruleInputMode text
+// entry pattern in handleRuleControls.
+func handleHistoryControls(w *pixelgl.Window, g *gameOfLife) {
+	if g.historyInputMode {
+		g.historyInputText += w.Typed()
+		if w.JustPressed(pixelgl.KeyBackspace) && len(g.historyInputText) > 0 {
+			g.historyInputText = g.historyInputText[:len(g.historyInputText)-1]
+		}
+		if w.JustPressed(pixelgl.KeyEnter) {
+			if n, err := strconv.Atoi(g.historyInputText); err == nil {
+				g.JumpToGeneration(n)
+			}
+			g.historyInputMode = false
+			g.historyInputText = ""
+		}
+		if w.JustPressed(pixelgl.KeyEscape) {
+			g.historyInputMode = false
+			g.historyInputText = ""
+		}
+		return
+	}
+	if textEntryMode(g) != "" {
+		return
+	}
+
+	if w.JustPressed(pixelgl.KeyZ) {
+		g.Undo()
+	}
+	if w.JustPressed(pixelgl.KeyV) {
+		g.Redo()
+	}
+	if w.JustPressed(pixelgl.KeyG) {
+		g.historyInputMode = true
+		g.historyInputText = ""
+	}
+}
+
+// handleRuleControls cycles built-in presets with R and opens a free-form
+// rulestring input with /, reusing win.Typed() for keyboard text entry.
+func handleRuleControls(w *pixelgl.Window, g *gameOfLife) {
+	if g.ruleInputMode {
+		g.ruleInputText += w.Typed()
+		if w.JustPressed(pixelgl.KeyBackspace) && len(g.ruleInputText) > 0 {
+			g.ruleInputText = g.ruleInputText[:len(g.ruleInputText)-1]
+		}
+		if w.JustPressed(pixelgl.KeyEnter) {
+			if r, err := ParseRule(g.ruleInputText); err == nil {
+				g.SetRule(r)
+			}
+			g.ruleInputMode = false
+			g.ruleInputText = ""
+		}
+		if w.JustPressed(pixelgl.KeyEscape) {
+			g.ruleInputMode = false
+			g.ruleInputText = ""
+		}
+		return
+	}
+	if textEntryMode(g) != "" {
+		return
+	}
+
+	if w.JustPressed(pixelgl.KeyR) {
+		g.ruleIndex = (g.ruleIndex + 1) % len(rulePresets)
+		r, _ := ParseRule(rulePresets[g.ruleIndex].Rule)
+		g.SetRule(r)
+	}
+	if w.JustPressed(pixelgl.KeySlash) {
+		g.ruleInputMode = true
+		g.ruleInputText = ""
+	}
+}
+
+// handlePatternControls opens a filename prompt on O (load a .rle/.lif
+// pattern under the cursor) or X (export the current board as RLE), and
+// resolves the Y/N prompt shown when a loaded pattern's rule header
+// differs from the active rule.
+func handlePatternControls(w *pixelgl.Window, g *gameOfLife) {
+	if g.ruleMismatch != "" {
+		if w.JustPressed(pixelgl.KeyY) {
+			if r, err := ParseRule(g.ruleMismatch); err == nil {
+				g.SetRule(r)
+			}
+			g.ruleMismatch = ""
+		}
+		if w.JustPressed(pixelgl.KeyN) || w.JustPressed(pixelgl.KeyEscape) {
+			g.ruleMismatch = ""
+		}
+		return
+	}
+
+	if g.patternInputMode {
+		g.patternInputText += w.Typed()
+		if w.JustPressed(pixelgl.KeyBackspace) && len(g.patternInputText) > 0 {
+			g.patternInputText = g.patternInputText[:len(g.patternInputText)-1]
+		}
+		if w.JustPressed(pixelgl.KeyEnter) {
+			switch g.patternInputAction {
+			case "load":
+				g.loadPatternFile(w, g.patternInputText)
+			case "save":
+				g.savePatternFile(g.patternInputText)
+			}
+			g.patternInputMode = false
+			g.patternInputText = ""
+		}
+		if w.JustPressed(pixelgl.KeyEscape) {
+			g.patternInputMode = false
+			g.patternInputText = ""
+		}
+		return
+	}
+	if textEntryMode(g) != "" {
+		return
+	}
+
+	if !paused {
+		return
+	}
+	if w.JustPressed(pixelgl.KeyO) {
+		g.patternInputMode = true
+		g.patternInputAction = "load"
+		g.patternInputText = ""
+	}
+	if w.JustPressed(pixelgl.KeyX) {
+		g.patternInputMode = true
+		g.patternInputAction = "save"
+		g.patternInputText = ""
+	}
+}
+
+// loadPatternFile decodes path as RLE (.rle) or Life 1.06 (.lif/.life) and
+// stamps it under the current mouse position. If the pattern carries a
+// rule header that differs from the active rule, it asks before switching.
+func (g *gameOfLife) loadPatternFile(w *pixelgl.Window, path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	var p *patterns.Pattern
+	if strings.HasSuffix(path, ".rle") {
+		p, err = patterns.LoadRLE(f)
+	} else {
+		p, err = patterns.LoadLife106(f)
+	}
+	if err != nil || p == nil {
+		return
+	}
+
+	if p.Rule != "" && p.Rule != g.rule.String() {
+		g.ruleMismatch = p.Rule
+	}
+
+	x, y := int(math.Floor(w.MousePosition().X/cellWidth)), int(math.Floor(w.MousePosition().Y/cellWidth))
+	p.StampInto(g, x, y)
+}
+
+// savePatternFile exports the current board as RLE to path.
+func (g *gameOfLife) savePatternFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	p := &patterns.Pattern{Width: g.size, Height: g.size, Rule: g.rule.String(), Cells: make([][]bool, g.size)}
+	for x := 0; x < g.size; x++ {
+		p.Cells[x] = make([]bool, g.size)
+		for y := 0; y < g.size; y++ {
+			p.Cells[x][y] = g.currentState[x][y]
+		}
+	}
+	return patterns.SaveRLE(f, p)
 }
 
 func handlePause(w *pixelgl.Window) {
@@ -169,7 +394,7 @@ func handlePause(w *pixelgl.Window) {
 
 func highlightSquare(w *pixelgl.Window, g *gameOfLife, i *imdraw.IMDraw) {
 	x, y := int(math.Floor(w.MousePosition().X/cellWidth)), int(math.Floor(w.MousePosition().Y/cellWidth))
-	if x < cells && y < cells {
+	if x >= 0 && x < g.size && y >= 0 && y < g.size {
 		if !g.currentState[x][y] {
 			i.Color = colornames.Green
 		} else {
@@ -185,8 +410,10 @@ func highlightSquare(w *pixelgl.Window, g *gameOfLife, i *imdraw.IMDraw) {
 func handleMouseClick(w *pixelgl.Window, g *gameOfLife, i *imdraw.IMDraw) {
 	if w.JustPressed(pixelgl.MouseButtonLeft) {
 		x, y := int(math.Floor(w.MousePosition().X/cellWidth)), int(math.Floor(w.MousePosition().Y/cellWidth))
-		w.MousePosition()
-		g.currentState[x][y] = !g.currentState[x][y]
+		if x < 0 || x >= g.size || y < 0 || y >= g.size {
+			return
+		}
+		g.ToggleCell(x, y)
 		g.draw(i)
 	}
 }
@@ -202,33 +429,55 @@ func run() {
 		panic(err)
 	}
 
-	game := gameOfLife{}
+	game := gameOfLife{engine: engineFlag}
 	game.initialize()
 
 	imd := *imdraw.New(nil)
 
-	f := time.Tick(time.Second / fps)
+	render := time.Tick(time.Second / fps)
+	lastStep := time.Now()
 
 	for !win.Closed() {
 		handlePause(win)
+		handleRuleControls(win, &game)
+		handlePatternControls(win, &game)
+		handleSimControls(win, &game)
+		handleHistoryControls(win, &game)
 		win.Clear(colornames.White)
 		game.draw(&imd)
 		imd.Draw(win)
 
 		if !paused {
-			game.calculateNextState()
+			if time.Since(lastStep) >= time.Second/time.Duration(game.ticksPerSecond) {
+				game.calculateNextState()
+				lastStep = time.Now()
+			}
 		} else {
 			highlightSquare(win, &game, &imd)
 			handleMouseClick(win, &game, &imd)
 		}
-		drawMenu(&imd, win)
+		drawMenu(&imd, win, &game)
 
 		win.Update()
 
-		<-f
+		<-render
 	}
 }
 
+// engineFlag holds -engine, read by run() once pixelgl.Run has started its
+// own goroutine; package-level since pixelgl.Run only accepts a func().
+var engineFlag string
+
 func main() {
+	engine := flag.String("engine", EngineHashlife, "simulation engine for the bounded topology: hashlife or bitboard (for benchmarking against hashlife)")
+	flag.Parse()
+	switch *engine {
+	case EngineHashlife, EngineBitboard:
+		engineFlag = *engine
+	default:
+		fmt.Fprintf(os.Stderr, "unknown -engine %q, must be %q or %q; using %q\n", *engine, EngineHashlife, EngineBitboard, EngineHashlife)
+		engineFlag = EngineHashlife
+	}
+
 	pixelgl.Run(run)
 }
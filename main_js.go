@@ -0,0 +1,287 @@
+//go:build js && wasm
+// +build js,wasm
+
+// WebAssembly entrypoint: renders the same gameOfLife state as main.go's
+// desktop build, but to an HTML5 canvas via syscall/js instead of
+// pixel/glfw, so the simulator can be embedded on a page with no Go
+// toolchain on the client (see the Makefile's wasm target).
+package main
+
+import (
+	"strconv"
+	"strings"
+	"syscall/js"
+	"time"
+
+	"gameOfLife/patterns"
+)
+
+// render paints the board, and (while paused) the cursor's highlight
+// square, onto ctx the same way the desktop build's draw/highlightSquare
+// do: black for live cells, green/red under the cursor for the cell a
+// click would add or remove. Canvas y grows downward, so rows are drawn
+// flipped to match the desktop build's bottom-left origin.
+func render(ctx js.Value, g *gameOfLife, mouseX, mouseY int) {
+	ctx.Set("fillStyle", "white")
+	ctx.Call("fillRect", 0, 0, g.size*cellWidth, g.size*cellWidth)
+
+	ctx.Set("fillStyle", "black")
+	for i, row := range g.currentState {
+		for j, alive := range row {
+			if alive {
+				ctx.Call("fillRect", i*cellWidth, (g.size-1-j)*cellWidth, cellWidth, cellWidth)
+			}
+		}
+	}
+
+	if paused && mouseX >= 0 && mouseX < g.size && mouseY >= 0 && mouseY < g.size {
+		if g.currentState[mouseX][mouseY] {
+			ctx.Set("fillStyle", "red")
+		} else {
+			ctx.Set("fillStyle", "green")
+		}
+		ctx.Call("fillRect", mouseX*cellWidth, (g.size-1-mouseY)*cellWidth, cellWidth, cellWidth)
+	}
+}
+
+// renderStatus writes the current generation counter, rule, and hotkey
+// hints into the page's #status element, since the canvas has no room for
+// the desktop build's side menu text.
+func renderStatus(status js.Value, g *gameOfLife) {
+	status.Set("innerText", "Generation: "+strconv.Itoa(g.generation)+"   Rule: "+g.rule.String()+
+		"   R: next rule   /: type rule   O: load   X: save   Z: undo   V: redo   G: jump to gen")
+}
+
+// loadPatternText decodes text (named, so its extension picks the format,
+// mirroring desktop's loadPatternFile) as RLE or Life 1.06 and stamps it
+// into g at (x, y), confirming first if the pattern's rule header differs
+// from the active rule — js's confirm() blocks until answered, so unlike
+// the desktop build's ruleMismatch/Y-N prompt this can resolve inline.
+func loadPatternText(g *gameOfLife, name, text string, x, y int) {
+	var p *patterns.Pattern
+	var err error
+	if strings.HasSuffix(name, ".rle") {
+		p, err = patterns.LoadRLE(strings.NewReader(text))
+	} else {
+		p, err = patterns.LoadLife106(strings.NewReader(text))
+	}
+	if err != nil || p == nil {
+		return
+	}
+
+	if p.Rule != "" && p.Rule != g.rule.String() {
+		ok := js.Global().Call("confirm", "Pattern wants rule "+p.Rule+". Switch to it?").Bool()
+		if ok {
+			if r, err := ParseRule(p.Rule); err == nil {
+				g.SetRule(r)
+			}
+		}
+	}
+
+	p.StampInto(g, x, y)
+}
+
+// savePatternRLE exports g's current board as RLE and triggers a browser
+// download of it, the canvas equivalent of desktop's savePatternFile
+// writing to a chosen path.
+func savePatternRLE(g *gameOfLife) {
+	p := &patterns.Pattern{Width: g.size, Height: g.size, Rule: g.rule.String(), Cells: make([][]bool, g.size)}
+	for x := 0; x < g.size; x++ {
+		p.Cells[x] = make([]bool, g.size)
+		for y := 0; y < g.size; y++ {
+			p.Cells[x][y] = g.currentState[x][y]
+		}
+	}
+
+	var out strings.Builder
+	if err := patterns.SaveRLE(&out, p); err != nil {
+		return
+	}
+
+	blob := js.Global().Get("Blob").New(
+		js.ValueOf([]interface{}{out.String()}),
+		map[string]interface{}{"type": "text/plain"},
+	)
+	url := js.Global().Get("URL").Call("createObjectURL", blob)
+	a := js.Global().Get("document").Call("createElement", "a")
+	a.Set("href", url)
+	a.Set("download", "pattern.rle")
+	a.Call("click")
+	js.Global().Get("URL").Call("revokeObjectURL", url)
+}
+
+// canvasCell converts a mouse event's page coordinates to board cell
+// coordinates, mirroring desktop's w.MousePosition()/cellWidth but
+// flipping y (against the current board size) to account for the
+// canvas's top-left origin.
+func canvasCell(canvas, event js.Value, size int) (x, y int) {
+	rect := canvas.Call("getBoundingClientRect")
+	px := event.Get("clientX").Float() - rect.Get("left").Float()
+	py := event.Get("clientY").Float() - rect.Get("top").Float()
+	return int(px) / cellWidth, size - 1 - int(py)/cellWidth
+}
+
+// resizeCanvas matches canvas's backing pixel size to g's board so
+// canvasCell's coordinate math stays correct after a resize.
+func resizeCanvas(canvas js.Value, g *gameOfLife) {
+	canvas.Set("width", g.size*cellWidth)
+	canvas.Set("height", g.size*cellWidth)
+}
+
+func main() {
+	doc := js.Global().Get("document")
+	canvas := doc.Call("getElementById", "game")
+	ctx := canvas.Call("getContext", "2d")
+	status := doc.Call("getElementById", "status")
+
+	game := &gameOfLife{}
+	game.initialize()
+	resizeCanvas(canvas, game)
+
+	mouseX, mouseY := -1, -1
+
+	canvas.Call("addEventListener", "mousemove", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		mouseX, mouseY = canvasCell(canvas, args[0], game.size)
+		return nil
+	}))
+
+	// handleMouseClick semantics: only toggles a cell while paused.
+	canvas.Call("addEventListener", "mousedown", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if !paused {
+			return nil
+		}
+		x, y := canvasCell(canvas, args[0], game.size)
+		if x >= 0 && x < game.size && y >= 0 && y < game.size {
+			game.ToggleCell(x, y)
+		}
+		return nil
+	}))
+
+	fileInput := doc.Call("getElementById", "pattern-file")
+
+	// loadFromFile reads file (a JS File, from either the hidden input or
+	// a canvas drop) and stamps it at (x, y) via loadPatternText once the
+	// browser finishes reading it — FileReader is callback-based, unlike
+	// the desktop build's synchronous os.Open.
+	loadFromFile := func(file js.Value, x, y int) {
+		name := file.Get("name").String()
+		reader := js.Global().Get("FileReader").New()
+		reader.Set("onload", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			loadPatternText(game, name, reader.Get("result").String(), x, y)
+			return nil
+		}))
+		reader.Call("readAsText", file)
+	}
+
+	fileInput.Call("addEventListener", "change", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		files := fileInput.Get("files")
+		if files.Get("length").Int() > 0 {
+			loadFromFile(files.Index(0), mouseX, mouseY)
+		}
+		return nil
+	}))
+
+	// Dropping a file directly onto the canvas is the drag-and-drop
+	// alternative to the O hotkey's file dialog; dragover must be
+	// prevented by default or the browser refuses the drop.
+	canvas.Call("addEventListener", "dragover", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		args[0].Call("preventDefault")
+		return nil
+	}))
+	canvas.Call("addEventListener", "drop", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		event := args[0]
+		event.Call("preventDefault")
+		files := event.Get("dataTransfer").Get("files")
+		if files.Get("length").Int() > 0 {
+			x, y := canvasCell(canvas, event, game.size)
+			loadFromFile(files.Index(0), x, y)
+		}
+		return nil
+	}))
+
+	// keydown wires handlePause (P), handleSimControls' topology toggle
+	// (T), resize ([ / ]), speed (, / .), single-step (S, while paused),
+	// handleRuleControls' preset cycling (R) and custom rule entry (/, via
+	// a prompt() since the canvas has no text-input widget),
+	// handlePatternControls' load (O, opening the hidden file input) and
+	// save (X, triggering a download), and handleHistoryControls'
+	// undo/redo (Z/V) and jump-to-generation (G, also via prompt()) to the
+	// same keys as the desktop build.
+	doc.Call("addEventListener", "keydown", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		switch args[0].Get("key").String() {
+		case "p", "P":
+			paused = !paused
+		case "t", "T":
+			game.wrap = !game.wrap
+		case "[":
+			game.Resize(game.size - cellSizeStep)
+			resizeCanvas(canvas, game)
+		case "]":
+			game.Resize(game.size + cellSizeStep)
+			resizeCanvas(canvas, game)
+		case ",":
+			if game.ticksPerSecond > minTicksPerSecond {
+				game.ticksPerSecond -= ticksPerSecondStep
+				if game.ticksPerSecond < minTicksPerSecond {
+					game.ticksPerSecond = minTicksPerSecond
+				}
+			}
+		case ".":
+			if game.ticksPerSecond < maxTicksPerSecond {
+				game.ticksPerSecond += ticksPerSecondStep
+				if game.ticksPerSecond > maxTicksPerSecond {
+					game.ticksPerSecond = maxTicksPerSecond
+				}
+			}
+		case "s", "S":
+			if paused {
+				game.calculateNextState()
+			}
+		case "r", "R":
+			game.ruleIndex = (game.ruleIndex + 1) % len(rulePresets)
+			r, _ := ParseRule(rulePresets[game.ruleIndex].Rule)
+			game.SetRule(r)
+		case "/":
+			answer := js.Global().Call("prompt", "Rule (e.g. B3/S23):", game.rule.String())
+			if !answer.IsNull() && !answer.IsUndefined() {
+				if r, err := ParseRule(answer.String()); err == nil {
+					game.SetRule(r)
+				}
+			}
+		case "o", "O":
+			if paused {
+				fileInput.Call("click")
+			}
+		case "x", "X":
+			if paused {
+				savePatternRLE(game)
+			}
+		case "z", "Z":
+			game.Undo()
+		case "v", "V":
+			game.Redo()
+		case "g", "G":
+			answer := js.Global().Call("prompt", "Jump to generation:")
+			if !answer.IsNull() && !answer.IsUndefined() {
+				if n, err := strconv.Atoi(answer.String()); err == nil {
+					game.JumpToGeneration(n)
+				}
+			}
+		}
+		return nil
+	}))
+
+	lastStep := time.Now()
+	tick := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		render(ctx, game, mouseX, mouseY)
+		renderStatus(status, game)
+		if !paused && time.Since(lastStep) >= time.Second/time.Duration(game.ticksPerSecond) {
+			game.calculateNextState()
+			lastStep = time.Now()
+		}
+		return nil
+	})
+	js.Global().Call("setInterval", tick, 1000/fps)
+
+	select {} // keep the goroutine (and its event listeners) alive
+}
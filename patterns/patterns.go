@@ -0,0 +1,309 @@
+// Package patterns decodes and encodes Life pattern files so the game can
+// load the existing library of named patterns (glider guns, spaceships,
+// oscillators) instead of only starting from random noise.
+package patterns
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Pattern is a decoded Life pattern: a rectangular bounding box of alive
+// cells relative to (0,0), plus the rulestring the pattern was authored
+// for (empty if the source format doesn't carry one).
+type Pattern struct {
+	Width, Height int
+	Rule          string
+	Cells         [][]bool // Cells[x][y], x in [0,Width), y in [0,Height)
+}
+
+// alive reports whether (x, y) is set in the pattern.
+func (p *Pattern) alive(x, y int) bool {
+	return x >= 0 && x < p.Width && y >= 0 && y < p.Height &&
+		x < len(p.Cells) && y < len(p.Cells[x]) && p.Cells[x][y]
+}
+
+// set marks (x, y) alive, growing Cells if needed.
+func (p *Pattern) set(x, y int) {
+	if x >= p.Width {
+		p.Width = x + 1
+	}
+	if y >= p.Height {
+		p.Height = y + 1
+	}
+	for len(p.Cells) <= x {
+		p.Cells = append(p.Cells, nil)
+	}
+	for len(p.Cells[x]) <= y {
+		p.Cells[x] = append(p.Cells[x], false)
+	}
+	p.Cells[x][y] = true
+}
+
+// board is the subset of gameOfLife that StampInto needs; the main package
+// satisfies it without patterns importing pixel/glfw.
+type board interface {
+	SetAlive(x, y int, alive bool)
+	Bounds() (w, h int)
+}
+
+// StampInto paints p into g with its top-left corner at (x, y), clipping
+// against the board edges. Cells outside the pattern's bounding box are
+// left untouched, so stamping onto an existing board overlays rather than
+// clears it.
+func (p *Pattern) StampInto(g board, x, y int) {
+	w, h := g.Bounds()
+	for px := 0; px < p.Width; px++ {
+		for py := 0; py < p.Height; py++ {
+			if !p.alive(px, py) {
+				continue
+			}
+			bx, by := x+px, y+py
+			if bx < 0 || bx >= w || by < 0 || by >= h {
+				continue
+			}
+			g.SetAlive(bx, by, true)
+		}
+	}
+}
+
+// LoadRLE decodes the run-length-encoded Life format: an optional header
+// line "x = W, y = H, rule = B3/S23", run-length <count><tag> pairs where
+// tag is 'b' (dead), 'o' (alive) or '$' (end of row), terminated by '!',
+// and '#'-prefixed comment lines anywhere before the body.
+func LoadRLE(r io.Reader) (*Pattern, error) {
+	scanner := bufio.NewScanner(r)
+	p := &Pattern{}
+
+	var header string
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		header = line
+		break
+	}
+	if header == "" {
+		return nil, fmt.Errorf("patterns: RLE file has no header")
+	}
+	if err := parseRLEHeader(header, p); err != nil {
+		return nil, err
+	}
+
+	var body strings.Builder
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		body.WriteString(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := parseRLEBody(body.String(), p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func parseRLEHeader(line string, p *Pattern) error {
+	if !strings.HasPrefix(line, "x") {
+		return fmt.Errorf("patterns: RLE header missing: %q", line)
+	}
+	for _, field := range strings.Split(line, ",") {
+		field = strings.TrimSpace(field)
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key, val := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		switch key {
+		case "x":
+			n, err := strconv.Atoi(val)
+			if err != nil {
+				return fmt.Errorf("patterns: bad RLE width %q", val)
+			}
+			p.Width = n
+		case "y":
+			n, err := strconv.Atoi(val)
+			if err != nil {
+				return fmt.Errorf("patterns: bad RLE height %q", val)
+			}
+			p.Height = n
+		case "rule":
+			p.Rule = val
+		}
+	}
+	for len(p.Cells) < p.Width {
+		p.Cells = append(p.Cells, make([]bool, p.Height))
+	}
+	return nil
+}
+
+func parseRLEBody(body string, p *Pattern) error {
+	x, y := 0, 0
+	count := 0
+	for _, c := range body {
+		if c >= '0' && c <= '9' {
+			count = count*10 + int(c-'0')
+			continue
+		}
+		n := count
+		if n == 0 {
+			n = 1
+		}
+		count = 0
+
+		switch c {
+		case 'b':
+			x += n
+		case 'o':
+			for i := 0; i < n; i++ {
+				p.set(x, y)
+				x++
+			}
+		case '$':
+			y += n
+			x = 0
+		case '!':
+			return nil
+		default:
+			return fmt.Errorf("patterns: unexpected RLE tag %q", c)
+		}
+	}
+	return fmt.Errorf("patterns: RLE body missing terminating '!'")
+}
+
+// SaveRLE encodes p in run-length Life format, with an "x = W, y = H, rule
+// = ..." header when p.Rule is set.
+func SaveRLE(w io.Writer, p *Pattern) error {
+	if p.Rule != "" {
+		if _, err := fmt.Fprintf(w, "x = %d, y = %d, rule = %s\n", p.Width, p.Height, p.Rule); err != nil {
+			return err
+		}
+	} else {
+		if _, err := fmt.Fprintf(w, "x = %d, y = %d\n", p.Width, p.Height); err != nil {
+			return err
+		}
+	}
+
+	var body strings.Builder
+	for y := 0; y < p.Height; y++ {
+		runTag := byte(0)
+		runLen := 0
+		flush := func() {
+			if runLen == 0 {
+				return
+			}
+			if runLen > 1 {
+				fmt.Fprintf(&body, "%d", runLen)
+			}
+			body.WriteByte(runTag)
+			runLen = 0
+		}
+		for x := 0; x < p.Width; x++ {
+			tag := byte('b')
+			if p.alive(x, y) {
+				tag = 'o'
+			}
+			if tag != runTag {
+				flush()
+				runTag = tag
+			}
+			runLen++
+		}
+		flush()
+		if y < p.Height-1 {
+			body.WriteByte('$')
+		}
+	}
+	body.WriteByte('!')
+
+	_, err := io.WriteString(w, wrapRLE(body.String(), 70)+"\n")
+	return err
+}
+
+// wrapRLE breaks an RLE body into lines no longer than width, the
+// convention used by most Life pattern collections.
+func wrapRLE(s string, width int) string {
+	var out strings.Builder
+	for len(s) > width {
+		out.WriteString(s[:width])
+		out.WriteByte('\n')
+		s = s[width:]
+	}
+	out.WriteString(s)
+	return out.String()
+}
+
+// LoadLife106 decodes the older, simpler Life 1.06 format: a "#Life 1.06"
+// header followed by one "x y" coordinate pair per live cell. Coordinates
+// are collected before any call to set, since Life 1.06 files may use
+// coordinates centered on the origin (including negative ones) and set
+// can't grow Cells backwards to accommodate them.
+func LoadLife106(r io.Reader) (*Pattern, error) {
+	scanner := bufio.NewScanner(r)
+	type coord struct{ x, y int }
+	var coords []coord
+	minX, minY := 0, 0
+	first := true
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("patterns: Life 1.06 expects \"x y\" pairs, got %q", line)
+		}
+		x, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("patterns: bad Life 1.06 x %q", fields[0])
+		}
+		y, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("patterns: bad Life 1.06 y %q", fields[1])
+		}
+		if first || x < minX {
+			minX = x
+		}
+		if first || y < minY {
+			minY = y
+		}
+		first = false
+		coords = append(coords, coord{x, y})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	p := &Pattern{}
+	for _, c := range coords {
+		p.set(c.x-minX, c.y-minY)
+	}
+	return p, nil
+}
+
+// SaveLife106 encodes p in the Life 1.06 coordinate-list format.
+func SaveLife106(w io.Writer, p *Pattern) error {
+	if _, err := io.WriteString(w, "#Life 1.06\n"); err != nil {
+		return err
+	}
+	for x := 0; x < p.Width; x++ {
+		for y := 0; y < p.Height; y++ {
+			if p.alive(x, y) {
+				if _, err := fmt.Fprintf(w, "%d %d\n", x, y); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
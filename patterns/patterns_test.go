@@ -0,0 +1,61 @@
+package patterns
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadLife106_OriginCenteredCoordinates(t *testing.T) {
+	p, err := LoadLife106(strings.NewReader("#Life 1.06\n-1 -1\n0 0\n1 1\n"))
+	if err != nil {
+		t.Fatalf("LoadLife106: %v", err)
+	}
+	want := [][2]int{{0, 0}, {1, 1}, {2, 2}}
+	for _, c := range want {
+		if !p.alive(c[0], c[1]) {
+			t.Errorf("expected (%d,%d) alive after normalizing, got dead", c[0], c[1])
+		}
+	}
+	if p.Width != 3 || p.Height != 3 {
+		t.Errorf("got bounds %dx%d, want 3x3", p.Width, p.Height)
+	}
+}
+
+func TestLoadLife106_AlreadyOriginAligned(t *testing.T) {
+	p, err := LoadLife106(strings.NewReader("#Life 1.06\n0 0\n1 0\n2 0\n"))
+	if err != nil {
+		t.Fatalf("LoadLife106: %v", err)
+	}
+	for x := 0; x < 3; x++ {
+		if !p.alive(x, 0) {
+			t.Errorf("expected (%d,0) alive", x)
+		}
+	}
+}
+
+func TestLoadRLE_RoundTrip(t *testing.T) {
+	const glider = "x = 3, y = 3, rule = B3/S23\nbo$2bo$3o!\n"
+	p, err := LoadRLE(strings.NewReader(glider))
+	if err != nil {
+		t.Fatalf("LoadRLE: %v", err)
+	}
+	if p.Rule != "B3/S23" {
+		t.Errorf("got rule %q, want B3/S23", p.Rule)
+	}
+
+	var out strings.Builder
+	if err := SaveRLE(&out, p); err != nil {
+		t.Fatalf("SaveRLE: %v", err)
+	}
+	reloaded, err := LoadRLE(strings.NewReader(out.String()))
+	if err != nil {
+		t.Fatalf("LoadRLE(round-trip): %v", err)
+	}
+	for x := 0; x < p.Width; x++ {
+		for y := 0; y < p.Height; y++ {
+			if p.alive(x, y) != reloaded.alive(x, y) {
+				t.Errorf("round-trip mismatch at (%d,%d): got %v, want %v", x, y, reloaded.alive(x, y), p.alive(x, y))
+			}
+		}
+	}
+}